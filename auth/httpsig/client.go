@@ -0,0 +1,72 @@
+package httpsig
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client wraps an *http.Client and signs every outgoing request with the
+// given keyId and private key, so a peer running Middleware can verify
+// it and recover the signer as the request's Actor.
+type Client struct {
+	HTTPClient *http.Client
+	KeyID      string
+	PrivateKey *rsa.PrivateKey
+}
+
+// NewClient returns a Client that signs requests as keyID using key.
+func NewClient(keyID string, key *rsa.PrivateKey) *Client {
+	return &Client{HTTPClient: http.DefaultClient, KeyID: keyID, PrivateKey: key}
+}
+
+// Post signs and sends an HTTP POST of body to url with the given
+// content type.
+func (c *Client) Post(url, contentType string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if err := c.sign(req, body); err != nil {
+		return nil, err
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+func (c *Client) sign(req *http.Request, body []byte) error {
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("Host", req.URL.Host)
+	digest := sha256.Sum256(body)
+	req.Header.Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(digest[:]))
+
+	headers := []string{"(request-target)", "host", "date", "digest"}
+	signed, err := signingString(req, headers)
+	if err != nil {
+		return err
+	}
+
+	hashed := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, c.PrivateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Signature", fmt.Sprintf(
+		`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		c.KeyID, strings.Join(headers, " "), base64.StdEncoding.EncodeToString(sig),
+	))
+	return nil
+}
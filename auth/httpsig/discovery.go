@@ -0,0 +1,55 @@
+package httpsig
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+)
+
+// Discovery is the /.well-known/rainbow document a Rainbow server
+// publishes so peers can resolve its public key and see which SDUI
+// actions it accepts over federated, signed requests.
+type Discovery struct {
+	KeyID     string   `json:"keyId"`
+	PublicKey string   `json:"publicKeyPem"`
+	Actions   []string `json:"actions"`
+}
+
+// DiscoveryHandler serves doc as the /.well-known/rainbow document.
+func DiscoveryHandler(doc Discovery) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(doc)
+	}
+}
+
+// EncodePublicKeyPEM PEM-encodes pub for embedding in a Discovery
+// document's PublicKey field.
+func EncodePublicKeyPEM(pub *rsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), nil
+}
+
+// DecodePublicKeyPEM parses a PEM-encoded RSA public key, as fetched from
+// a peer's Discovery document, for use as a KeyResolver result.
+func DecodePublicKeyPEM(pemStr string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(pemStr))
+	if block == nil {
+		return nil, errors.New("httpsig: invalid PEM public key")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaPub, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("httpsig: not an RSA public key")
+	}
+	return rsaPub, nil
+}
@@ -0,0 +1,156 @@
+package httpsig
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating test key: %v", err)
+	}
+	return key
+}
+
+func TestMiddlewareAcceptsValidSignature(t *testing.T) {
+	key := testKey(t)
+	const keyID = "https://peer.example/actor"
+
+	var gotActor string
+	srv := httptest.NewServer(Middleware(func(id string) (crypto.PublicKey, error) {
+		return &key.PublicKey, nil
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotActor = Actor(r.Context())
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	})))
+	defer srv.Close()
+
+	client := NewClient(keyID, key)
+	resp, err := client.Post(srv.URL+"/sdui", "application/json", []byte(`{"action":"refresh"}`))
+	if err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if gotActor != keyID {
+		t.Errorf("Actor = %q, want %q", gotActor, keyID)
+	}
+}
+
+func TestMiddlewareRejectsTamperedBody(t *testing.T) {
+	key := testKey(t)
+	const keyID = "https://peer.example/actor"
+
+	srv := httptest.NewServer(Middleware(func(id string) (crypto.PublicKey, error) {
+		return &key.PublicKey, nil
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+	defer srv.Close()
+
+	client := NewClient(keyID, key)
+	signed, err := http.NewRequest(http.MethodPost, srv.URL+"/sdui", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.sign(signed, []byte(`{"action":"refresh"}`)); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	// Replay the signed headers with a swapped body: a valid signature
+	// over the original body must not authorize this different payload.
+	tampered := []byte(`{"action":"delete-everything"}`)
+	signed.Body = io.NopCloser(bytes.NewReader(tampered))
+	signed.ContentLength = int64(len(tampered))
+
+	resp, err := http.DefaultClient.Do(signed)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401 for tampered body", resp.StatusCode)
+	}
+}
+
+// signWithHeaders signs req over exactly headers - bypassing Client.sign,
+// which always includes "digest" - so tests can reproduce a signer who
+// never covered the body at all.
+func signWithHeaders(t *testing.T, req *http.Request, keyID string, key *rsa.PrivateKey, headers []string) {
+	t.Helper()
+	req.Header.Set("Date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("Host", req.URL.Host)
+
+	signed, err := signingString(req, headers)
+	if err != nil {
+		t.Fatalf("signingString: %v", err)
+	}
+	hashed := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		t.Fatalf("SignPKCS1v15: %v", err)
+	}
+
+	req.Header.Set("Signature", fmt.Sprintf(
+		`keyId="%s",algorithm="rsa-sha256",headers="%s",signature="%s"`,
+		keyID, strings.Join(headers, " "), base64.StdEncoding.EncodeToString(sig),
+	))
+}
+
+func TestMiddlewareRejectsForgedBodyWhenDigestNotSigned(t *testing.T) {
+	key := testKey(t)
+	const keyID = "https://peer.example/actor"
+
+	srv := httptest.NewServer(Middleware(func(id string) (crypto.PublicKey, error) {
+		return &key.PublicKey, nil
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+	defer srv.Close()
+
+	// Sign over only (request-target) host date, the default headers list
+	// when a signer never opts into covering the body at all.
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/sdui", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signWithHeaders(t, req, keyID, key, []string{"(request-target)", "host", "date"})
+
+	// An attacker who doesn't have the private key can still forge a body
+	// and attach a Digest header that honestly describes it: the digest
+	// was never part of what the signature covers, so the self-consistent
+	// Digest header alone must not be enough to let it through.
+	forged := []byte(`{"action":"delete-everything"}`)
+	digest := sha256.Sum256(forged)
+	req.Header.Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(digest[:]))
+	req.Body = io.NopCloser(bytes.NewReader(forged))
+	req.ContentLength = int64(len(forged))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want 401 for a forged body the signature never covered", resp.StatusCode)
+	}
+}
@@ -1,15 +1,48 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"html/template"
 	"log"
 	"math/rand"
 	"net/http"
 	"time"
+
+	"github.com/zulubit/rain/interactions"
+	"github.com/zulubit/rain/render"
+	"github.com/zulubit/rain/sdui"
+	"github.com/zulubit/rain/sources"
 )
 
+const commentsCollection = "comments"
+
+const csrfToken = "demo-token"
+
+// demoBlogroll is the OPML a real deployment would instead fetch from a
+// <rain-source src="...opml">-declared URL; it's inlined here so the
+// demo has no network dependency.
+const demoBlogroll = `<?xml version="1.0"?>
+<opml version="2.0">
+  <head><title>Rainbow demo blogroll</title></head>
+  <body>
+    <outline text="Friends" title="Friends">
+      <outline type="demo" text="Rainbow Blog" title="Rainbow Blog" xmlUrl="https://example.com/rainbow/feed"/>
+    </outline>
+  </body>
+</opml>`
+
+func init() {
+	// Registered under the "demo" outline type so the demo can merge
+	// sources without reaching out to the network; a real deployment
+	// would rely on the "rss" Fetcher registered by default.
+	sources.Register("demo", func(ctx context.Context, cache *sources.Cache, url string) ([]sources.Item, error) {
+		return []sources.Item{
+			{Title: "Rainbow makes server-driven UI simple", Link: url},
+		}, nil
+	})
+}
+
 func getRandomMessage() string {
 	messages := []string{
 		"Hello from the server!",
@@ -21,93 +54,84 @@ func getRandomMessage() string {
 	return messages[rand.Intn(len(messages))]
 }
 
-const htmlTemplate = `<!DOCTYPE html>
-<html>
-<head>
-    <title>Rainbow Simple Demo</title>
-    <style>
-        /* Prevent FOUC */
-        :not(:defined) { visibility: hidden; }
-        
-        body {
-            font-family: system-ui, sans-serif;
-            max-width: 600px;
-            margin: 50px auto;
-            padding: 20px;
-        }
-    </style>
-</head>
-<body>
-    <rain-bow 
-        page-data='{{.PageData}}' 
-        global-data='{{.GlobalData}}'>
-        
-        <simple-message></simple-message>
-    </rain-bow>
-
-    <script type="module" src="/static/app.js"></script>
-</body>
-</html>`
-
 func main() {
-	tmpl := template.Must(template.New("main").Parse(htmlTemplate))
+	r := render.New(render.Options{Templates: "views/"})
+
+	blogroll, err := sources.ParseOPML([]byte(demoBlogroll))
+	if err != nil {
+		log.Fatalf("parsing demo blogroll: %v", err)
+	}
+	cache := sources.NewCache(5 * time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cache.StartRefresher(ctx, time.Minute)
+
+	mergeSources := func(ctx context.Context) (map[string][]sources.Item, error) {
+		// Merge replaces the demo's old blocking sleep. Outlines handled by
+		// the "rss" Fetcher read through cache; the "demo" Fetcher above
+		// returns its items directly and never touches cache.
+		return sources.Merge(ctx, cache, blogroll)
+	}
+
+	comments := interactions.NewMemoryStore()
+
+	router := sdui.NewRouter(func(*http.Request) string { return csrfToken })
+	router.Handle("refresh-message", func(ctx *sdui.Context, _ json.RawMessage) (sdui.Patch, error) {
+		ctx.Flash("Message updated!")
+		return sdui.Patch{sdui.Replace("/message", getRandomMessage())}, nil
+	})
+	router.Handle("refresh-sources", func(ctx *sdui.Context, _ json.RawMessage) (sdui.Patch, error) {
+		merged, err := mergeSources(ctx.Context)
+		if err != nil {
+			return nil, err
+		}
+		return sdui.Patch{sdui.Replace("/sources", merged)}, nil
+	})
+	router.Handle("refresh-comments", func(ctx *sdui.Context, _ json.RawMessage) (sdui.Patch, error) {
+		recent, err := interactions.PageData(ctx.Context, comments, commentsCollection, 20)
+		if err != nil {
+			return nil, err
+		}
+		return sdui.Patch{sdui.Replace("/comments", recent)}, nil
+	})
 
 	// Serve static files
 	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("static/"))))
 
+	// SDUI action endpoint
+	http.Handle("/sdui", router)
+
+	// Comment submission and moderation
+	http.Handle("/comments", interactions.Handler(comments, commentsCollection))
+	http.Handle("/comments/delete", interactions.DeleteHandler(comments, commentsCollection, func(*http.Request) string { return csrfToken }))
+
 	// Main page
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		// Handle AJAX update requests
-		if r.Method == "POST" {
-			// Simulate server processing with 2s delay
-			time.Sleep(2 * time.Second)
-
-			// Pick a new random message
-			message := getRandomMessage()
-
-			response := map[string]interface{}{
-				"pageData": map[string]interface{}{
-					"message": message,
-				},
-				"globalData": map[string]interface{}{
-					"csrf_token": "demo-token",
-					"flash":      []string{"Message updated!"},
-				},
-			}
-
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(response)
+	http.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		merged, err := mergeSources(req.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		recent, err := interactions.PageData(req.Context(), comments, commentsCollection, 20)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-
-		// Regular page load
-		message := getRandomMessage()
 
 		pageData := map[string]interface{}{
-			"message": message,
+			"message":  getRandomMessage(),
+			"sources":  merged,
+			"comments": recent,
 		}
-
 		globalData := map[string]interface{}{
-			"csrf_token": "demo-token",
+			"csrf_token": csrfToken,
 			"flash":      []string{},
 		}
 
-		pageDataJSON, _ := json.Marshal(pageData)
-		globalDataJSON, _ := json.Marshal(globalData)
-
-		data := struct {
-			PageData   template.JS
-			GlobalData template.JS
-		}{
-			PageData:   template.JS(pageDataJSON),
-			GlobalData: template.JS(globalDataJSON),
-		}
-
-		tmpl.Execute(w, data)
+		r.SDUI(w, http.StatusOK, "index", pageData, globalData)
 	})
 
 	fmt.Println("Simple Rainbow Demo")
 	fmt.Println("http://localhost:8081")
 	log.Fatal(http.ListenAndServe(":8081", nil))
 }
-
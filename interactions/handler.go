@@ -0,0 +1,93 @@
+package interactions
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// postBody is the POST {name, message} payload Handler accepts.
+type postBody struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
+
+// Handler returns an http.HandlerFunc that accepts POST {name, message}
+// into collection: it validates that both fields are non-empty after
+// sanitizing message, stamps PostDate, and rate-limits by remote IP. CSRF
+// verification is expected to happen upstream, against the same token
+// threaded through globalData.csrf_token (e.g. by wrapping this handler
+// with an sdui.Router action).
+func Handler(store Store, collection string) http.HandlerFunc {
+	limiter := NewRateLimiter(5, time.Minute)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "interactions: method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if !limiter.Allow(remoteIP(r)) {
+			http.Error(w, "interactions: rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		var body postBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "interactions: malformed body", http.StatusBadRequest)
+			return
+		}
+		body.Name = strings.TrimSpace(body.Name)
+		body.Message = sanitize(body.Message)
+		if body.Name == "" || body.Message == "" {
+			http.Error(w, "interactions: name and message are required", http.StatusBadRequest)
+			return
+		}
+
+		entry := Entry{
+			ID:       newID(),
+			Name:     body.Name,
+			Message:  body.Message,
+			PostDate: time.Now(),
+		}
+		if err := store.Insert(r.Context(), collection, entry); err != nil {
+			http.Error(w, "interactions: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(entry)
+	}
+}
+
+// PageData returns the newest limit entries in collection sorted by
+// PostDate descending, ready to assign to pageData.comments on the next
+// render.
+func PageData(ctx context.Context, store Store, collection string, limit int) ([]Entry, error) {
+	entries, err := store.List(ctx, collection, limit)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].PostDate.After(entries[j].PostDate) })
+	return entries, nil
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,85 @@
+package interactions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeEscapesUnterminatedTags(t *testing.T) {
+	got := sanitize(`<svg/onload=alert(1)`)
+	if strings.Contains(got, "<") {
+		t.Errorf("sanitize(%q) = %q, still contains a literal \"<\"", `<svg/onload=alert(1)`, got)
+	}
+}
+
+func TestHandlerStoresAndRateLimits(t *testing.T) {
+	store := NewMemoryStore()
+	handler := Handler(store, "comments")
+
+	post := func(name, message string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]string{"name": name, "message": message})
+		req := httptest.NewRequest(http.MethodPost, "/comments", strings.NewReader(string(body)))
+		req.RemoteAddr = "203.0.113.5:1234"
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w
+	}
+
+	w := post("Ada", "hello there")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201, body = %s", w.Code, w.Body.String())
+	}
+
+	w = post("", "missing name")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400 for empty name", w.Code)
+	}
+
+	entries, err := store.List(context.Background(), "comments", 10)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("len(entries) = %d, want 1", len(entries))
+	}
+
+	// Exhaust the rate limit for this IP.
+	var limited bool
+	for i := 0; i < 10; i++ {
+		if post("Ada", "again").Code == http.StatusTooManyRequests {
+			limited = true
+			break
+		}
+	}
+	if !limited {
+		t.Error("expected rate limit to trigger after repeated posts from the same IP")
+	}
+}
+
+func TestPageDataSortsNewestFirst(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	older := Entry{ID: "1", Name: "A", Message: "first"}
+	newer := Entry{ID: "2", Name: "B", Message: "second"}
+	older.PostDate = older.PostDate.Add(0)
+	newer.PostDate = older.PostDate.Add(1)
+
+	if err := store.Insert(ctx, "comments", older); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Insert(ctx, "comments", newer); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := PageData(ctx, store, "comments", 10)
+	if err != nil {
+		t.Fatalf("PageData: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != "2" {
+		t.Errorf("entries = %+v, want newest (id=2) first", entries)
+	}
+}
@@ -0,0 +1,54 @@
+package interactions
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore is an in-process Store, useful for demos and tests where
+// pulling in a real SQLite driver or Mongo deployment isn't warranted.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string][]Entry
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string][]Entry)}
+}
+
+// Insert stores e under collection.
+func (s *MemoryStore) Insert(ctx context.Context, collection string, e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[collection] = append(s.entries[collection], e)
+	return nil
+}
+
+// List returns up to the newest limit entries in collection, in
+// insertion order; PageData is responsible for the final sort.
+func (s *MemoryStore) List(ctx context.Context, collection string, limit int) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all := s.entries[collection]
+	if limit > 0 && len(all) > limit {
+		all = all[len(all)-limit:]
+	}
+	out := make([]Entry, len(all))
+	copy(out, all)
+	return out, nil
+}
+
+// Delete removes the entry with id from collection.
+func (s *MemoryStore) Delete(ctx context.Context, collection, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := s.entries[collection]
+	for i, e := range entries {
+		if e.ID == id {
+			s.entries[collection] = append(entries[:i], entries[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package interactions
+
+import "net/http"
+
+// DeleteHandler returns an http.HandlerFunc for moderation: it deletes
+// the entry named by the "id" query parameter from collection, guarded
+// by the same csrf_token contract already threaded through globalData.
+// csrfToken returns the expected token for a given request.
+func DeleteHandler(store Store, collection string, csrfToken func(*http.Request) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+			http.Error(w, "interactions: method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if csrfToken != nil && r.URL.Query().Get("csrf_token") != csrfToken(r) {
+			http.Error(w, "interactions: bad csrf token", http.StatusForbidden)
+			return
+		}
+
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			http.Error(w, "interactions: missing id", http.StatusBadRequest)
+			return
+		}
+		if err := store.Delete(r.Context(), collection, id); err != nil {
+			http.Error(w, "interactions: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,50 @@
+package interactions
+
+import "context"
+
+// MongoCollection is the storage shape MongoStore needs: insert one
+// document, find the documents matching a filter (already decoded into
+// Entry values), and delete one document matching a filter. It is
+// deliberately not the same shape as *mongo.Collection (from
+// go.mongodb.org/mongo-driver/mongo) - that type's InsertOne returns
+// (*mongo.InsertOneResult, error) and Find returns (*mongo.Cursor,
+// error), not a decoded []Entry - so this package carries no hard
+// dependency on the driver. An adapter over the real collection must do
+// the result/cursor handling itself; it is not a drop-in wrapper.
+type MongoCollection interface {
+	InsertOne(ctx context.Context, document interface{}) error
+	Find(ctx context.Context, filter map[string]interface{}, limit int64) ([]Entry, error)
+	DeleteOne(ctx context.Context, filter map[string]interface{}) error
+}
+
+// MongoStore is a Store backed by a single Mongo collection holding
+// every interaction kind, distinguished by the "collection" field.
+type MongoStore struct {
+	coll MongoCollection
+}
+
+// NewMongoStore wraps coll.
+func NewMongoStore(coll MongoCollection) *MongoStore {
+	return &MongoStore{coll: coll}
+}
+
+// Insert stores e, tagging it with collection.
+func (s *MongoStore) Insert(ctx context.Context, collection string, e Entry) error {
+	doc := struct {
+		Entry      `bson:",inline"`
+		Collection string `bson:"collection"`
+	}{Entry: e, Collection: collection}
+	return s.coll.InsertOne(ctx, doc)
+}
+
+// List returns the entries coll.Find yields for collection, capped at
+// limit. It passes no sort order, so which entries come back, and in
+// what order, is up to the MongoCollection implementation.
+func (s *MongoStore) List(ctx context.Context, collection string, limit int) ([]Entry, error) {
+	return s.coll.Find(ctx, map[string]interface{}{"collection": collection}, int64(limit))
+}
+
+// Delete removes the entry with id from collection.
+func (s *MongoStore) Delete(ctx context.Context, collection, id string) error {
+	return s.coll.DeleteOne(ctx, map[string]interface{}{"collection": collection, "id": id})
+}
@@ -0,0 +1,44 @@
+package interactions
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter is a sliding-window limiter keyed by an arbitrary string,
+// used by Handler to cap submissions per remote IP.
+type RateLimiter struct {
+	mu     sync.Mutex
+	hits   map[string][]time.Time
+	limit  int
+	window time.Duration
+}
+
+// NewRateLimiter allows up to limit calls to Allow per key within
+// window.
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{hits: make(map[string][]time.Time), limit: limit, window: window}
+}
+
+// Allow reports whether key is still under its limit, recording the call
+// if so.
+func (rl *RateLimiter) Allow(key string) bool {
+	now := time.Now()
+	cutoff := now.Add(-rl.window)
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	kept := rl.hits[key][:0]
+	for _, h := range rl.hits[key] {
+		if h.After(cutoff) {
+			kept = append(kept, h)
+		}
+	}
+	if len(kept) >= rl.limit {
+		rl.hits[key] = kept
+		return false
+	}
+	rl.hits[key] = append(kept, now)
+	return true
+}
@@ -0,0 +1,17 @@
+package interactions
+
+import (
+	"html"
+	"strings"
+)
+
+// sanitize HTML-escapes s so any markup a submitter includes renders as
+// literal text instead of being interpreted by the browser. A
+// tag-stripping regex is not enough here - an unterminated tag like
+// "<svg/onload=alert(1)" has no closing ">" and would pass straight
+// through - so this escapes rather than attempts to strip. Swap in
+// bluemonday's StrictPolicy (or a stricter one) if message bodies ever
+// need to allow a safe subset of markup.
+func sanitize(s string) string {
+	return html.EscapeString(strings.TrimSpace(s))
+}
@@ -0,0 +1,68 @@
+package interactions
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// SQLiteStore is a Store backed by database/sql. Pass in a *sql.DB
+// opened with any SQLite driver (e.g. mattn/go-sqlite3 or
+// modernc.org/sqlite); this package only depends on the stdlib
+// database/sql interfaces.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore wraps db, creating the interactions table if it does
+// not already exist.
+func NewSQLiteStore(db *sql.DB) (*SQLiteStore, error) {
+	const schema = `CREATE TABLE IF NOT EXISTS interactions (
+		id TEXT PRIMARY KEY,
+		collection TEXT NOT NULL,
+		name TEXT NOT NULL,
+		message TEXT NOT NULL,
+		post_date DATETIME NOT NULL
+	)`
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("interactions: creating table: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Insert stores e under collection.
+func (s *SQLiteStore) Insert(ctx context.Context, collection string, e Entry) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO interactions (id, collection, name, message, post_date) VALUES (?, ?, ?, ?, ?)`,
+		e.ID, collection, e.Name, e.Message, e.PostDate)
+	return err
+}
+
+// List returns the newest limit entries in collection, ordered by
+// post_date descending.
+func (s *SQLiteStore) List(ctx context.Context, collection string, limit int) ([]Entry, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, name, message, post_date FROM interactions WHERE collection = ? ORDER BY post_date DESC LIMIT ?`,
+		collection, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var e Entry
+		if err := rows.Scan(&e.ID, &e.Name, &e.Message, &e.PostDate); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// Delete removes the entry with id from collection.
+func (s *SQLiteStore) Delete(ctx context.Context, collection, id string) error {
+	_, err := s.db.ExecContext(ctx,
+		`DELETE FROM interactions WHERE collection = ? AND id = ?`, collection, id)
+	return err
+}
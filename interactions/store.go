@@ -0,0 +1,27 @@
+// Package interactions persists user-submitted data - comments,
+// reactions, form posts - and streams it back through the SDUI
+// pageData envelope, so the demo's single comment form generalizes to
+// any collection of moderated, rate-limited user input.
+package interactions
+
+import (
+	"context"
+	"time"
+)
+
+// Entry is a single persisted interaction.
+type Entry struct {
+	ID       string    `json:"id" bson:"id"`
+	Name     string    `json:"name" bson:"name"`
+	Message  string    `json:"message" bson:"message"`
+	PostDate time.Time `json:"postDate" bson:"postDate"`
+}
+
+// Store persists Entries under a named collection (e.g. "comments",
+// "reactions"), so Handler can mix multiple kinds of user-submitted data
+// behind one contract.
+type Store interface {
+	Insert(ctx context.Context, collection string, e Entry) error
+	List(ctx context.Context, collection string, limit int) ([]Entry, error)
+	Delete(ctx context.Context, collection, id string) error
+}
@@ -0,0 +1,179 @@
+// Package render centralizes response rendering for Rainbow servers:
+// HTML page shells, JSON/XML payloads, and the SDUI page-data/global-data
+// envelope that <rain-bow> expects, so handlers no longer hand-roll
+// template.Must/json.Marshal/json.NewEncoder calls individually.
+package render
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// Options configures a Render.
+type Options struct {
+	// Templates is a directory glob-loaded on New, e.g. "views/*.html".
+	// A bare directory path is expanded to "<dir>/*.html".
+	Templates string
+	// Funcs are made available to every template.
+	Funcs template.FuncMap
+	// Minify strips leading/trailing whitespace from each rendered line.
+	// It's a cheap line-level pass, not a full HTML minifier.
+	Minify bool
+}
+
+// Render renders HTML templates and JSON/XML/text payloads, and knows how
+// to inject the SDUI page-data/global-data envelope into an HTML shell.
+type Render struct {
+	opts Options
+	tmpl *template.Template
+}
+
+// New parses opts.Templates (if set) and returns a ready-to-use Render.
+func New(opts Options) *Render {
+	r := &Render{opts: opts}
+	if opts.Templates != "" {
+		pattern := opts.Templates
+		if !strings.ContainsAny(filepath.Base(pattern), "*?[") {
+			pattern = filepath.Join(pattern, "*.html")
+		}
+		r.tmpl = template.Must(template.New("").Funcs(opts.Funcs).ParseGlob(pattern))
+	}
+	return r
+}
+
+// HTML executes the named template with data and writes it with status.
+func (r *Render) HTML(w http.ResponseWriter, status int, name string, data interface{}) error {
+	if r.tmpl == nil {
+		return fmt.Errorf("render: no templates loaded (Options.Templates is empty)")
+	}
+	var buf strings.Builder
+	if err := r.tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return err
+	}
+	body := buf.String()
+	if r.opts.Minify {
+		body = minify(body)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_, err := w.Write([]byte(body))
+	return err
+}
+
+// JSON marshals v and writes it with status.
+func (r *Render) JSON(w http.ResponseWriter, status int, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_, err = w.Write(b)
+	return err
+}
+
+// XML marshals v and writes it with status.
+func (r *Render) XML(w http.ResponseWriter, status int, v interface{}) error {
+	b, err := xml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(status)
+	_, err = w.Write(b)
+	return err
+}
+
+// Text writes s as plain text with status.
+func (r *Render) Text(w http.ResponseWriter, status int, s string) error {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	_, err := w.Write([]byte(s))
+	return err
+}
+
+// Data writes b as-is with status, without setting a Content-Type.
+func (r *Render) Data(w http.ResponseWriter, status int, b []byte) error {
+	w.WriteHeader(status)
+	_, err := w.Write(b)
+	return err
+}
+
+// Negotiate inspects the request's Accept header to pick JSON, XML, or
+// HTML (via htmlName) for v, so a single handler can serve both the
+// initial page load and an AJAX update. HTML is chosen when htmlName is
+// set and Accept is empty or contains "text/html" or "*/*"; otherwise XML
+// when Accept mentions "xml", and JSON in every other case.
+func (r *Render) Negotiate(w http.ResponseWriter, req *http.Request, status int, htmlName string, v interface{}) error {
+	switch {
+	case acceptsHTML(req) && htmlName != "":
+		return r.HTML(w, status, htmlName, v)
+	case acceptsXML(req):
+		return r.XML(w, status, v)
+	default:
+		return r.JSON(w, status, v)
+	}
+}
+
+// SDUI renders name with pageData and globalData injected as JSON,
+// assigned to a plain string field rather than cast through the unsafe
+// template.JS type - so html/template applies its normal HTML-attribute
+// escaping to the page-data/global-data attributes. That escaping
+// entity-encodes ', ", <, >, & and + (protecting against breaking out of
+// the quoted attribute); it leaves the JSON otherwise untouched, so the
+// browser's attribute decoding hands the client the original JSON back
+// unharmed. template.JSEscapeString is for embedding inside a <script>
+// JS string literal, where the JS lexer would un-escape it again - doing
+// that here instead double-escapes quotes and produces JSON the client
+// can't parse. The template receives an SDUIData value, which carries
+// only the PageData and GlobalData strings.
+func (r *Render) SDUI(w http.ResponseWriter, status int, name string, pageData, globalData interface{}) error {
+	pd, err := marshalJSON(pageData)
+	if err != nil {
+		return err
+	}
+	gd, err := marshalJSON(globalData)
+	if err != nil {
+		return err
+	}
+	return r.HTML(w, status, name, SDUIData{PageData: pd, GlobalData: gd})
+}
+
+// SDUIData is the template data SDUI passes to ExecuteTemplate: the
+// page-data and global-data attribute values for <rain-bow>, ready for
+// html/template's attribute auto-escaping.
+type SDUIData struct {
+	PageData   string
+	GlobalData string
+}
+
+func marshalJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func acceptsHTML(req *http.Request) bool {
+	accept := req.Header.Get("Accept")
+	return accept == "" || strings.Contains(accept, "text/html") || strings.Contains(accept, "*/*")
+}
+
+func acceptsXML(req *http.Request) bool {
+	return strings.Contains(req.Header.Get("Accept"), "xml")
+}
+
+func minify(body string) string {
+	lines := strings.Split(body, "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		out = append(out, strings.TrimSpace(line))
+	}
+	return strings.Join(out, "\n")
+}
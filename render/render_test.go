@@ -0,0 +1,121 @@
+package render
+
+import (
+	"encoding/json"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var pageDataAttr = regexp.MustCompile(`page-data='([^']*)'`)
+
+// extractPageData pulls the page-data attribute value out of rendered
+// HTML and reverses the browser's attribute-decoding step, the way a
+// <rain-bow> element's getAttribute("page-data") would see it.
+func extractPageData(t *testing.T, body string) string {
+	t.Helper()
+	m := pageDataAttr.FindStringSubmatch(body)
+	if m == nil {
+		t.Fatalf("no page-data attribute found in body: %s", body)
+	}
+	return html.UnescapeString(m[1])
+}
+
+func newTestRender(t *testing.T) *Render {
+	t.Helper()
+	dir := t.TempDir()
+	tmpl := `{{define "index"}}<rain-bow page-data='{{.PageData}}' global-data='{{.GlobalData}}'></rain-bow>{{end}}`
+	if err := os.WriteFile(filepath.Join(dir, "index.html"), []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return New(Options{Templates: dir})
+}
+
+func TestSDUIEscapesAttributeBreakout(t *testing.T) {
+	r := newTestRender(t)
+	w := httptest.NewRecorder()
+
+	// A message containing a single quote must not be able to close the
+	// page-data attribute early and inject markup.
+	pageData := map[string]interface{}{"message": `'><script>alert(1)</script>`}
+	globalData := map[string]interface{}{"csrf_token": "tok"}
+
+	if err := r.SDUI(w, http.StatusOK, "index", pageData, globalData); err != nil {
+		t.Fatalf("SDUI: %v", err)
+	}
+
+	body := w.Body.String()
+	if strings.Contains(body, "<script>") {
+		t.Errorf("rendered body contains an unescaped <script> tag: %s", body)
+	}
+	if !strings.Contains(body, "page-data='") {
+		t.Errorf("expected page-data attribute in body: %s", body)
+	}
+
+	// The whole point of the attribute is that client JS can read it back
+	// out with getAttribute() and JSON.parse() it; round-trip that here.
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(extractPageData(t, body)), &decoded); err != nil {
+		t.Fatalf("page-data attribute did not round-trip through JSON: %v", err)
+	}
+	if decoded["message"] != `'><script>alert(1)</script>` {
+		t.Errorf("decoded message = %q, want original payload preserved", decoded["message"])
+	}
+}
+
+func TestSDUIRoundTripsPlainJSON(t *testing.T) {
+	r := newTestRender(t)
+	w := httptest.NewRecorder()
+
+	pageData := map[string]interface{}{"message": "hello"}
+	globalData := map[string]interface{}{"csrf_token": "tok"}
+
+	if err := r.SDUI(w, http.StatusOK, "index", pageData, globalData); err != nil {
+		t.Fatalf("SDUI: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(extractPageData(t, w.Body.String())), &decoded); err != nil {
+		t.Fatalf("page-data attribute did not round-trip through JSON: %v", err)
+	}
+	if decoded["message"] != "hello" {
+		t.Errorf("decoded message = %q, want %q", decoded["message"], "hello")
+	}
+}
+
+func TestNegotiatePicksJSONByDefault(t *testing.T) {
+	r := New(Options{})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+
+	if err := r.Negotiate(w, req, http.StatusOK, "", map[string]string{"hello": "world"}); err != nil {
+		t.Fatalf("Negotiate: %v", err)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.Contains(ct, "application/json") {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	if !strings.Contains(w.Body.String(), `"hello":"world"`) {
+		t.Errorf("body = %q, want JSON payload", w.Body.String())
+	}
+}
+
+func TestNegotiatePicksHTMLWhenAccepted(t *testing.T) {
+	r := newTestRender(t)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+
+	data := SDUIData{PageData: "{}", GlobalData: "{}"}
+	if err := r.Negotiate(w, req, http.StatusOK, "index", data); err != nil {
+		t.Fatalf("Negotiate: %v", err)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.Contains(ct, "text/html") {
+		t.Errorf("Content-Type = %q, want text/html", ct)
+	}
+}
@@ -0,0 +1,208 @@
+// Package sdui implements the server-driven UI action protocol consumed
+// by the <rain-bow> custom element: the client posts an action envelope
+// and the server replies with a JSON Patch (RFC 6902) describing how to
+// mutate pageData/globalData, instead of re-sending the full blob.
+package sdui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Op is a single RFC 6902 JSON Patch operation.
+type Op struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// Patch is an ordered list of JSON Patch operations applied to the
+// client's reactive pageData/globalData state.
+type Patch []Op
+
+// Replace returns a "replace" patch operation for path.
+func Replace(path string, value interface{}) Op {
+	return Op{Op: "replace", Path: path, Value: value}
+}
+
+// Add returns an "add" patch operation for path.
+func Add(path string, value interface{}) Op {
+	return Op{Op: "add", Path: path, Value: value}
+}
+
+// Remove returns a "remove" patch operation for path.
+func Remove(path string) Op {
+	return Op{Op: "remove", Path: path}
+}
+
+// Envelope is the request body a <rain-bow> client posts to run an
+// action.
+type Envelope struct {
+	Action    string          `json:"action"`
+	Target    string          `json:"target"`
+	Args      json.RawMessage `json:"args"`
+	CSRFToken string          `json:"csrf_token"`
+}
+
+// Result is the response body for an action: the patch to apply to
+// client state, any flash entries to surface, and the server's revision
+// counter at the time the action completed.
+type Result struct {
+	Patch    Patch    `json:"patch"`
+	Flash    []string `json:"flash,omitempty"`
+	Revision int64    `json:"revision"`
+}
+
+// ActionFunc handles a single SDUI action and returns the patch to send
+// back to the client.
+type ActionFunc func(ctx *Context, args json.RawMessage) (Patch, error)
+
+// Context carries per-request state into an ActionFunc, including the
+// ability to push SSE progress events for long-running actions.
+type Context struct {
+	context.Context
+	Request *http.Request
+	Target  string
+
+	w       http.ResponseWriter
+	flusher http.Flusher
+	stream  bool
+	flash   []string
+}
+
+// Progress pushes an "event: progress" SSE frame to the client. It is a
+// no-op when the request did not negotiate streaming (see ServeHTTP).
+func (c *Context) Progress(data interface{}) error {
+	if !c.stream {
+		return nil
+	}
+	return writeEvent(c.w, c.flusher, "progress", data)
+}
+
+// Flash queues msg to be surfaced in the action's Result.Flash. It may
+// be called any number of times; every call this action made is
+// included, in order.
+func (c *Context) Flash(msg string) {
+	c.flash = append(c.flash, msg)
+}
+
+func writeEvent(w http.ResponseWriter, flusher http.Flusher, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+// ErrUnknownAction is returned when an envelope names an action that was
+// never registered with Handle.
+var ErrUnknownAction = errors.New("sdui: unknown action")
+
+// ErrBadCSRFToken is returned when the envelope's csrf_token does not
+// match the token the router expects for this request.
+var ErrBadCSRFToken = errors.New("sdui: bad csrf token")
+
+// Router dispatches SDUI action envelopes to registered ActionFuncs. It
+// verifies the envelope's csrf_token against the value the caller's
+// globalData.csrf_token was seeded with, and streams interim progress
+// events over SSE when the client requests text/event-stream.
+type Router struct {
+	mu       sync.RWMutex
+	actions  map[string]ActionFunc
+	csrf     func(*http.Request) string
+	revision int64
+}
+
+// NewRouter creates an empty Router. csrfToken returns the expected CSRF
+// token for a given request, typically the same value used to seed
+// globalData.csrf_token for that session.
+func NewRouter(csrfToken func(*http.Request) string) *Router {
+	return &Router{
+		actions: make(map[string]ActionFunc),
+		csrf:    csrfToken,
+	}
+}
+
+// Handle registers fn under name. Posting an envelope naming this action
+// dispatches to fn.
+func (rt *Router) Handle(name string, fn ActionFunc) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.actions[name] = fn
+}
+
+// ServeHTTP decodes an Envelope from the request body, verifies its CSRF
+// token, and dispatches it to the registered action. If the request
+// Accept header is "text/event-stream", any Context.Progress calls made
+// by the action are streamed as "event: progress" frames before the
+// final "event: patch" frame; otherwise a single JSON Result is written.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var env Envelope
+	if err := json.NewDecoder(r.Body).Decode(&env); err != nil {
+		http.Error(w, "sdui: malformed envelope", http.StatusBadRequest)
+		return
+	}
+
+	if rt.csrf != nil && env.CSRFToken != rt.csrf(r) {
+		http.Error(w, ErrBadCSRFToken.Error(), http.StatusForbidden)
+		return
+	}
+
+	rt.mu.RLock()
+	fn, ok := rt.actions[env.Action]
+	rt.mu.RUnlock()
+	if !ok {
+		http.Error(w, ErrUnknownAction.Error(), http.StatusNotFound)
+		return
+	}
+
+	stream := r.Header.Get("Accept") == "text/event-stream"
+	ctx := &Context{
+		Context: r.Context(),
+		Request: r,
+		Target:  env.Target,
+		w:       w,
+		stream:  stream,
+	}
+
+	if stream {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "sdui: streaming unsupported", http.StatusNotImplemented)
+			return
+		}
+		ctx.flusher = flusher
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+	}
+
+	patch, err := fn(ctx, env.Args)
+	revision := atomic.AddInt64(&rt.revision, 1)
+	if err != nil {
+		if stream {
+			writeEvent(w, ctx.flusher, "error", map[string]string{"error": err.Error()})
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	result := Result{Patch: patch, Flash: ctx.flash, Revision: revision}
+	if stream {
+		writeEvent(w, ctx.flusher, "patch", result)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
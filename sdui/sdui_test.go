@@ -0,0 +1,68 @@
+package sdui
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServeHTTPThreadsFlashAndPatch(t *testing.T) {
+	rt := NewRouter(func(*http.Request) string { return "demo-token" })
+	rt.Handle("greet", func(ctx *Context, _ json.RawMessage) (Patch, error) {
+		ctx.Flash("hi")
+		ctx.Flash("again")
+		return Patch{Replace("/message", "hello")}, nil
+	})
+
+	body := `{"action":"greet","csrf_token":"demo-token"}`
+	req := httptest.NewRequest(http.MethodPost, "/sdui", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	rt.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200, body = %s", w.Code, w.Body.String())
+	}
+
+	var result Result
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decoding result: %v", err)
+	}
+	if len(result.Patch) != 1 || result.Patch[0].Path != "/message" || result.Patch[0].Value != "hello" {
+		t.Errorf("Patch = %+v, want single replace of /message", result.Patch)
+	}
+	if want := []string{"hi", "again"}; !equalStrings(result.Flash, want) {
+		t.Errorf("Flash = %v, want %v", result.Flash, want)
+	}
+}
+
+func TestServeHTTPRejectsBadCSRFToken(t *testing.T) {
+	rt := NewRouter(func(*http.Request) string { return "demo-token" })
+	rt.Handle("greet", func(ctx *Context, _ json.RawMessage) (Patch, error) {
+		return nil, nil
+	})
+
+	body := `{"action":"greet","csrf_token":"wrong"}`
+	req := httptest.NewRequest(http.MethodPost, "/sdui", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	rt.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", w.Code)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package sources
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCacheFetchReusesFreshEntry(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte("body"))
+	}))
+	defer srv.Close()
+
+	cache := NewCache(time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		body, err := cache.Fetch(ctx, srv.URL)
+		if err != nil {
+			t.Fatalf("Fetch: %v", err)
+		}
+		if string(body) != "body" {
+			t.Fatalf("body = %q, want %q", body, "body")
+		}
+	}
+
+	if hits != 1 {
+		t.Errorf("server hit %d times, want 1 (entry should stay fresh)", hits)
+	}
+}
+
+func TestCacheFetchUsesConditionalGETOnExpiry(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte("body"))
+	}))
+	defer srv.Close()
+
+	cache := NewCache(0) // always stale, forcing a conditional GET every call
+	ctx := context.Background()
+
+	if _, err := cache.Fetch(ctx, srv.URL); err != nil {
+		t.Fatalf("first Fetch: %v", err)
+	}
+	body, err := cache.Fetch(ctx, srv.URL)
+	if err != nil {
+		t.Fatalf("second Fetch: %v", err)
+	}
+
+	if hits != 2 {
+		t.Fatalf("server hit %d times, want 2", hits)
+	}
+	if string(body) != "body" {
+		t.Errorf("body = %q, want cached %q after a 304", body, "body")
+	}
+}
@@ -0,0 +1,44 @@
+package sources
+
+import "encoding/xml"
+
+// Document mirrors the OPML document shape of
+// github.com/kaorimatz/go-opml so a <rain-source src="...opml"> feed list
+// can be parsed without pulling in that dependency.
+type Document struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    Head     `xml:"head"`
+	Body    Body     `xml:"body"`
+}
+
+// Head is the OPML document's <head> block.
+type Head struct {
+	Title string `xml:"title"`
+}
+
+// Body holds the document's top-level outlines.
+type Body struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+// Outline is a single OPML <outline>, either a feed subscription
+// (XMLURL set) or a folder grouping nested Outlines.
+type Outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr"`
+	Type     string    `xml:"type,attr"`
+	XMLURL   string    `xml:"xmlUrl,attr"`
+	HTMLURL  string    `xml:"htmlUrl,attr"`
+	Outlines []Outline `xml:"outline"`
+}
+
+// ParseOPML parses an OPML document such as the one served at a
+// <rain-source>'s src URL.
+func ParseOPML(data []byte) (*Document, error) {
+	var doc Document
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
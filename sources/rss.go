@@ -0,0 +1,25 @@
+package sources
+
+import "encoding/xml"
+
+type rssFeed struct {
+	Channel struct {
+		Items []struct {
+			Title string `xml:"title"`
+			Link  string `xml:"link"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+// parseRSSItems extracts title/link pairs from an RSS 2.0 feed body.
+func parseRSSItems(body []byte) ([]Item, error) {
+	var feed rssFeed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return nil, err
+	}
+	items := make([]Item, 0, len(feed.Channel.Items))
+	for _, it := range feed.Channel.Items {
+		items = append(items, Item{Title: it.Title, Link: it.Link})
+	}
+	return items, nil
+}
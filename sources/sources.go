@@ -0,0 +1,99 @@
+// Package sources lets a Rainbow page declare external data feeds via
+// OPML (<rain-source src="/sources/news.opml">) and have the server
+// fetch, parse, and merge their items into pageData.sources on both the
+// initial render and the SDUI refresh action.
+package sources
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Item is a single entry merged into pageData.sources[<outlineTitle>].
+type Item struct {
+	Title string `json:"title"`
+	Link  string `json:"link"`
+}
+
+// Fetcher fetches the items behind one outline's feed URL, using cache
+// for conditional-GET reuse. Register plugs non-OPML fetchers (JSON
+// APIs, hand-rolled RSS) behind the same merged-data contract.
+type Fetcher func(ctx context.Context, cache *Cache, url string) ([]Item, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Fetcher{
+		"rss": fetchRSS,
+	}
+)
+
+// Register adds or replaces the Fetcher used for outlines whose "type"
+// attribute equals name.
+func Register(name string, fetcher Fetcher) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = fetcher
+}
+
+func lookup(name string) (Fetcher, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	fn, ok := registry[name]
+	return fn, ok
+}
+
+// Merge fetches every outline in doc through cache, dispatching on each
+// outline's "type" attribute (defaulting to "rss"), and returns the
+// items keyed by outline title - ready to assign to pageData.sources.
+// Outlines with no xmlUrl are folders (the normal shape of a blogroll,
+// where feeds are grouped under category outlines) and are recursed
+// into rather than skipped.
+func Merge(ctx context.Context, cache *Cache, doc *Document) (map[string][]Item, error) {
+	out := make(map[string][]Item)
+	if err := mergeOutlines(ctx, cache, doc.Body.Outlines, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func mergeOutlines(ctx context.Context, cache *Cache, outlines []Outline, out map[string][]Item) error {
+	for _, o := range outlines {
+		if o.XMLURL == "" {
+			if err := mergeOutlines(ctx, cache, o.Outlines, out); err != nil {
+				return err
+			}
+			continue
+		}
+
+		typ := o.Type
+		if typ == "" {
+			typ = "rss"
+		}
+		fn, ok := lookup(typ)
+		if !ok {
+			return fmt.Errorf("sources: no fetcher registered for outline type %q", typ)
+		}
+		items, err := fn(ctx, cache, o.XMLURL)
+		if err != nil {
+			return fmt.Errorf("sources: fetching %q: %w", o.Title, err)
+		}
+		title := o.Title
+		if title == "" {
+			title = o.Text
+		}
+		out[title] = items
+	}
+	return nil
+}
+
+// fetchRSS is the default Fetcher, registered for outline type "rss": it
+// pulls the feed body through cache and extracts each <item>'s
+// title/link.
+func fetchRSS(ctx context.Context, cache *Cache, url string) ([]Item, error) {
+	body, err := cache.Fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return parseRSSItems(body)
+}
@@ -0,0 +1,39 @@
+package sources
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMergeRecursesIntoFolderOutlines(t *testing.T) {
+	Register("test-merge", func(ctx context.Context, cache *Cache, url string) ([]Item, error) {
+		return []Item{{Title: "from " + url}}, nil
+	})
+
+	doc := &Document{
+		Body: Body{
+			Outlines: []Outline{
+				{
+					Text:  "Folder",
+					Title: "Folder",
+					Outlines: []Outline{
+						{Type: "test-merge", Title: "Nested Feed", XMLURL: "https://example.com/nested"},
+					},
+				},
+			},
+		},
+	}
+
+	merged, err := Merge(context.Background(), NewCache(0), doc)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	items, ok := merged["Nested Feed"]
+	if !ok {
+		t.Fatalf("merged = %v, want entry for feed nested under a folder outline", merged)
+	}
+	if len(items) != 1 || items[0].Title != "from https://example.com/nested" {
+		t.Errorf("items = %v, unexpected contents", items)
+	}
+}